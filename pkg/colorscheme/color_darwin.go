@@ -0,0 +1,31 @@
+//go:build darwin
+
+package colorscheme
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// detectPlatform reads macOS's global AppleInterfaceStyle default, which is
+// only set at all when the user has Dark Mode enabled.
+func detectPlatform() (Scheme, error) {
+	out, err := exec.Command("defaults", "read", "-g", "AppleInterfaceStyle").Output()
+	if err != nil {
+		if _, ok := err.(*exec.ExitError); ok {
+			// The key is unset in Light Mode, which "defaults read" reports
+			// as a non-zero exit rather than empty output.
+			return Light, nil
+		}
+
+		// "defaults" itself could not be run, e.g. missing from a
+		// sandboxed or headless environment.
+		return Light, ErrDetect
+	}
+
+	if strings.Contains(strings.ToLower(string(out)), "dark") {
+		return Dark, nil
+	}
+
+	return Light, nil
+}
@@ -0,0 +1,40 @@
+//go:build linux
+
+package colorscheme
+
+import "os/exec"
+
+// detectPlatform asks xdg-desktop-portal for the desktop's preferred
+// color scheme. Only works on Linux desktops running xdg-desktop-portal.
+func detectPlatform() (Scheme, error) {
+	cmd := exec.Command(
+		"busctl",
+		"--user",
+		"call",
+		"org.freedesktop.portal.Desktop",
+		"/org/freedesktop/portal/desktop",
+		"org.freedesktop.portal.Settings",
+		"Read",
+		"ss",
+		"org.freedesktop.appearance",
+		"color-scheme",
+	)
+
+	result, err := cmd.Output()
+	if err != nil {
+		return Light, ErrDetect
+	}
+
+	if len(result) < 2 {
+		return Light, ErrDetect
+	}
+
+	switch result[len(result)-2] {
+	case '1':
+		return Dark, nil
+	case '0', '2':
+		return Light, nil
+	}
+
+	return Light, ErrDetect
+}
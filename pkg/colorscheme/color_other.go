@@ -0,0 +1,9 @@
+//go:build !linux && !darwin && !windows
+
+package colorscheme
+
+// detectPlatform has no detector on this platform; Detect falls back to
+// $COLORFGBG.
+func detectPlatform() (Scheme, error) {
+	return Light, ErrDetect
+}
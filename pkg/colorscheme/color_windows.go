@@ -0,0 +1,26 @@
+//go:build windows
+
+package colorscheme
+
+import "golang.org/x/sys/windows/registry"
+
+// detectPlatform reads the AppsUseLightTheme value Windows' Settings app
+// writes under Personalize.
+func detectPlatform() (Scheme, error) {
+	key, err := registry.OpenKey(registry.CURRENT_USER, `Software\Microsoft\Windows\CurrentVersion\Themes\Personalize`, registry.QUERY_VALUE)
+	if err != nil {
+		return Light, ErrDetect
+	}
+	defer key.Close()
+
+	value, _, err := key.GetIntegerValue("AppsUseLightTheme")
+	if err != nil {
+		return Light, ErrDetect
+	}
+
+	if value == 0 {
+		return Dark, nil
+	}
+
+	return Light, nil
+}
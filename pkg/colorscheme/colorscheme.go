@@ -0,0 +1,79 @@
+// Package colorscheme detects whether the user prefers a light or dark
+// terminal theme, so howto can pick a matching glamour style.
+package colorscheme
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Scheme is the user's preferred color scheme.
+type Scheme uint8
+
+const (
+	Light Scheme = iota
+	Dark
+)
+
+// ErrDetect is returned when no detector, override, or env var could
+// determine a scheme, the caller should fall back to Light.
+var ErrDetect = errors.New("could not detect the user's preferred color scheme")
+
+// Detect resolves the color scheme to use, in priority order:
+//
+//  1. override, typically the `theme:` field in config.yaml
+//  2. $HOWTO_THEME
+//  3. the platform-specific detector (see color_*.go)
+//  4. $COLORFGBG, set by many terminal emulators, useful over SSH
+func Detect(override string) (Scheme, error) {
+	if s, ok := fromName(override); ok {
+		return s, nil
+	}
+
+	if s, ok := fromName(os.Getenv("HOWTO_THEME")); ok {
+		return s, nil
+	}
+
+	if s, err := detectPlatform(); err == nil {
+		return s, nil
+	}
+
+	if s, ok := fromColorFGBG(os.Getenv("COLORFGBG")); ok {
+		return s, nil
+	}
+
+	return Light, ErrDetect
+}
+
+func fromName(name string) (Scheme, bool) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "dark":
+		return Dark, true
+	case "light":
+		return Light, true
+	default:
+		return Light, false
+	}
+}
+
+// fromColorFGBG parses $COLORFGBG ("fg;bg", e.g. "15;0"). A background
+// value below 8 is one of the ANSI dark colors.
+func fromColorFGBG(raw string) (Scheme, bool) {
+	parts := strings.Split(raw, ";")
+	if len(parts) < 2 {
+		return Light, false
+	}
+
+	bg, err := strconv.Atoi(parts[len(parts)-1])
+	if err != nil {
+		return Light, false
+	}
+
+	if bg < 8 {
+		return Dark, true
+	}
+
+	return Light, true
+}
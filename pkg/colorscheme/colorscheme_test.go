@@ -0,0 +1,53 @@
+package colorscheme
+
+import "testing"
+
+func TestFromName(t *testing.T) {
+	tests := []struct {
+		name   string
+		raw    string
+		want   Scheme
+		wantOk bool
+	}{
+		{name: "dark", raw: "dark", want: Dark, wantOk: true},
+		{name: "light", raw: "light", want: Light, wantOk: true},
+		{name: "mixed case", raw: "DaRk", want: Dark, wantOk: true},
+		{name: "padded with whitespace", raw: "  dark  ", want: Dark, wantOk: true},
+		{name: "empty", raw: "", want: Light, wantOk: false},
+		{name: "unknown", raw: "solarized", want: Light, wantOk: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := fromName(tt.raw)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("fromName(%q) = (%v, %v), want (%v, %v)", tt.raw, got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestFromColorFGBG(t *testing.T) {
+	tests := []struct {
+		name   string
+		raw    string
+		want   Scheme
+		wantOk bool
+	}{
+		{name: "dark background", raw: "15;0", want: Dark, wantOk: true},
+		{name: "light background", raw: "0;15", want: Light, wantOk: true},
+		{name: "three-part value uses the last field", raw: "15;default;0", want: Dark, wantOk: true},
+		{name: "missing semicolon", raw: "15", want: Light, wantOk: false},
+		{name: "non-numeric background", raw: "15;none", want: Light, wantOk: false},
+		{name: "empty", raw: "", want: Light, wantOk: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := fromColorFGBG(tt.raw)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("fromColorFGBG(%q) = (%v, %v), want (%v, %v)", tt.raw, got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+// Package history persists every prompt and rendered response to a
+// SQLite database so users can look them up later or continue them.
+package history
+
+import (
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+var ErrNotFound = errors.New("no history entry with that id")
+
+// Entry is a single recorded prompt/response pair.
+type Entry struct {
+	ID        int64
+	Prompt    string
+	Response  string
+	Model     string
+	CreatedAt time.Time
+}
+
+// Store wraps the SQLite-backed history database.
+type Store struct {
+	db *sql.DB
+}
+
+// DBPath returns ~/.config/howto/history.db.
+func DBPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(homeDir, ".config", "howto", "history.db"), nil
+}
+
+// Open opens (creating if needed) the history database and applies its
+// schema.
+func Open() (*Store, error) {
+	path, err := DBPath()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, err
+	}
+
+	store := &Store{db: db}
+	if err := store.migrate(); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return store, nil
+}
+
+func (s *Store) migrate() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS entries (
+			id         INTEGER PRIMARY KEY AUTOINCREMENT,
+			prompt     TEXT NOT NULL,
+			response   TEXT NOT NULL,
+			model      TEXT NOT NULL,
+			created_at TIMESTAMP NOT NULL
+		)
+	`)
+
+	return err
+}
+
+// Close closes the underlying database handle.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Save records a new prompt/response pair and returns its id.
+func (s *Store) Save(prompt, response, model string) (int64, error) {
+	res, err := s.db.Exec(
+		`INSERT INTO entries (prompt, response, model, created_at) VALUES (?, ?, ?, ?)`,
+		prompt, response, model, time.Now(),
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.LastInsertId()
+}
+
+// List returns every entry, most recent first, optionally filtered to
+// prompts or responses containing search.
+func (s *Store) List(search string) ([]Entry, error) {
+	query := `SELECT id, prompt, response, model, created_at FROM entries`
+	args := []interface{}{}
+
+	if search != "" {
+		query += ` WHERE prompt LIKE ? OR response LIKE ?`
+		like := "%" + search + "%"
+		args = append(args, like, like)
+	}
+
+	query += ` ORDER BY created_at DESC`
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []Entry
+	for rows.Next() {
+		var e Entry
+		if err := rows.Scan(&e.ID, &e.Prompt, &e.Response, &e.Model, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+
+	return entries, rows.Err()
+}
+
+// Get fetches a single entry by id.
+func (s *Store) Get(id int64) (Entry, error) {
+	var e Entry
+	row := s.db.QueryRow(`SELECT id, prompt, response, model, created_at FROM entries WHERE id = ?`, id)
+	if err := row.Scan(&e.ID, &e.Prompt, &e.Response, &e.Model, &e.CreatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Entry{}, ErrNotFound
+		}
+		return Entry{}, err
+	}
+
+	return e, nil
+}
+
+// Delete removes an entry by id.
+func (s *Store) Delete(id int64) error {
+	res, err := s.db.Exec(`DELETE FROM entries WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
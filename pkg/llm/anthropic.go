@@ -0,0 +1,76 @@
+package llm
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+const anthropicAPI = "https://api.anthropic.com/v1/messages"
+
+// anthropic talks to Anthropic's messages API.
+type anthropic struct {
+	apiKey      string
+	model       string
+	temperature float64
+}
+
+func newAnthropic(profile Profile) (*anthropic, error) {
+	key, err := apiKey(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	return &anthropic{
+		apiKey:      key,
+		model:       profile.Model,
+		temperature: profile.Temperature,
+	}, nil
+}
+
+func (m *anthropic) Magic(message string) (string, error) {
+	reqBodyRaw, _ := json.Marshal(map[string]interface{}{
+		"model":       m.model,
+		"temperature": m.temperature,
+		"max_tokens":  1024,
+		"messages": []map[string]string{
+			{
+				"content": message,
+				"role":    "user",
+			},
+		},
+	})
+
+	reqBody := bytes.NewBuffer(reqBodyRaw)
+	req, _ := http.NewRequest(http.MethodPost, anthropicAPI, reqBody)
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("x-api-key", m.apiKey)
+	req.Header.Add("anthropic-version", "2023-06-01")
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+
+	defer res.Body.Close()
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return "", err
+	}
+
+	resContent := struct {
+		Content []struct {
+			Text string `json:"text"`
+		} `json:"content"`
+	}{}
+	if err := json.Unmarshal(resBody, &resContent); err != nil {
+		return "", err
+	}
+
+	if len(resContent.Content) == 0 {
+		return "", ErrLLMAPI
+	}
+
+	return resContent.Content[0].Text, nil
+}
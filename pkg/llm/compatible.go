@@ -0,0 +1,29 @@
+package llm
+
+// compatible talks to any generic OpenAI-compatible endpoint, such as
+// LocalAI or vLLM, which speak the same chat completions wire format as
+// OpenAI but are hosted at a user-supplied base URL and may not require an
+// API key at all.
+type compatible struct {
+	*openAI
+}
+
+func newCompatible(profile Profile) (*compatible, error) {
+	if profile.BaseURL == "" {
+		return nil, ErrMissingBaseURL
+	}
+
+	key, err := apiKey(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	return &compatible{
+		openAI: &openAI{
+			apiKey:      key,
+			baseURL:     profile.BaseURL,
+			model:       profile.Model,
+			temperature: profile.Temperature,
+		},
+	}, nil
+}
@@ -0,0 +1,143 @@
+package llm
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Profile is a named model configuration a user can declare in config.yaml
+// and select with `howto model <name>`.
+type Profile struct {
+	Name        string  `yaml:"name"`
+	Provider    string  `yaml:"provider"`
+	BaseURL     string  `yaml:"base_url,omitempty"`
+	Model       string  `yaml:"model"`
+	Temperature float64 `yaml:"temperature"`
+	APIKeyEnv   string  `yaml:"api_key_env,omitempty"`
+}
+
+// Config is the contents of ~/.config/howto/config.yaml.
+type Config struct {
+	DefaultModel string    `yaml:"default_model"`
+	Models       []Profile `yaml:"models"`
+	// Theme overrides color scheme detection when set to "light" or "dark".
+	Theme string `yaml:"theme,omitempty"`
+}
+
+// defaultConfig is used when no config file exists yet, so `howto` keeps
+// working out of the box against OpenAI like it always has.
+func defaultConfig() *Config {
+	return &Config{
+		DefaultModel: "gpt-3.5-turbo",
+		Models: []Profile{
+			{
+				Name:        "gpt-3.5-turbo",
+				Provider:    "openai",
+				Model:       "gpt-3.5-turbo",
+				Temperature: 0.1,
+				APIKeyEnv:   "OPENAI_API_KEY",
+			},
+		},
+	}
+}
+
+// ConfigPath returns ~/.config/howto/config.yaml.
+func ConfigPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(homeDir, ".config", "howto", "config.yaml"), nil
+}
+
+// LoadConfig reads the user's config file, falling back to defaultConfig if
+// none exists yet.
+func LoadConfig() (*Config, error) {
+	path, err := ConfigPath()
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return defaultConfig(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &Config{}
+	if err := yaml.Unmarshal(raw, cfg); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// Save writes the config back to ~/.config/howto/config.yaml.
+func (c *Config) Save() error {
+	path, err := ConfigPath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+
+	raw, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, raw, 0600)
+}
+
+// Profile looks up a named profile, falling back to DefaultModel when name
+// is empty.
+func (c *Config) Profile(name string) (Profile, error) {
+	if name == "" {
+		name = c.DefaultModel
+	}
+
+	for _, p := range c.Models {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+
+	return Profile{}, fmt.Errorf("no model profile named %q, see howto model --list", name)
+}
+
+// apiKey resolves a profile's API key from its configured env var, falling
+// back to the legacy ~/.config/howto/api.txt written by `howto api <key>`.
+func apiKey(profile Profile) (string, error) {
+	if profile.APIKeyEnv == "" {
+		return "", nil
+	}
+
+	if key := os.Getenv(profile.APIKeyEnv); key != "" {
+		return key, nil
+	}
+
+	if path, err := legacyAPIKeyPath(); err == nil {
+		if raw, err := os.ReadFile(path); err == nil {
+			return string(raw), nil
+		}
+	}
+
+	return "", ErrMissingAPIKey
+}
+
+func legacyAPIKeyPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(homeDir, ".config", "howto", "api.txt"), nil
+}
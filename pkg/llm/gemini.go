@@ -0,0 +1,81 @@
+package llm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+const geminiAPI = "https://generativelanguage.googleapis.com/v1beta/models/%s:generateContent?key=%s"
+
+// gemini talks to Google's Gemini API.
+type gemini struct {
+	apiKey      string
+	model       string
+	temperature float64
+}
+
+func newGemini(profile Profile) (*gemini, error) {
+	key, err := apiKey(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	return &gemini{
+		apiKey:      key,
+		model:       profile.Model,
+		temperature: profile.Temperature,
+	}, nil
+}
+
+func (m *gemini) Magic(message string) (string, error) {
+	reqBodyRaw, _ := json.Marshal(map[string]interface{}{
+		"contents": []map[string]interface{}{
+			{
+				"parts": []map[string]string{
+					{"text": message},
+				},
+			},
+		},
+		"generationConfig": map[string]interface{}{
+			"temperature": m.temperature,
+		},
+	})
+
+	reqBody := bytes.NewBuffer(reqBodyRaw)
+	url := fmt.Sprintf(geminiAPI, m.model, m.apiKey)
+	req, _ := http.NewRequest(http.MethodPost, url, reqBody)
+	req.Header.Add("Content-Type", "application/json")
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+
+	defer res.Body.Close()
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return "", err
+	}
+
+	resContent := struct {
+		Candidates []struct {
+			Content struct {
+				Parts []struct {
+					Text string `json:"text"`
+				} `json:"parts"`
+			} `json:"content"`
+		} `json:"candidates"`
+	}{}
+	if err := json.Unmarshal(resBody, &resContent); err != nil {
+		return "", err
+	}
+
+	if len(resContent.Candidates) == 0 || len(resContent.Candidates[0].Content.Parts) == 0 {
+		return "", ErrLLMAPI
+	}
+
+	return resContent.Candidates[0].Content.Parts[0].Text, nil
+}
@@ -0,0 +1,9 @@
+package llm
+
+// JSONProvider is implemented by providers that can constrain their output
+// to a JSON schema natively, such as OpenAI's response_format. Providers
+// that don't implement it are driven with a schema-in-prompt instruction
+// instead, see pkg/steps.
+type JSONProvider interface {
+	MagicJSON(message, schemaJSON string) (string, error)
+}
@@ -0,0 +1,35 @@
+// Package llm provides a pluggable interface for turning a task description
+// into step-by-step instructions, backed by any of several LLM providers.
+package llm
+
+import "errors"
+
+var (
+	ErrUnknownProvider = errors.New("unknown provider, use one of: openai, anthropic, gemini, ollama, openai-compatible")
+	ErrMissingAPIKey   = errors.New("missing API key for this provider, set the env var referenced by api_key_env")
+	ErrMissingBaseURL  = errors.New("missing base_url for this provider, set it in the model profile")
+	ErrLLMAPI          = errors.New("there was a problem with the LLM API while generating your response")
+)
+
+// Provider is implemented by every backend howto can talk to.
+type Provider interface {
+	Magic(message string) (string, error)
+}
+
+// New builds the Provider described by profile.
+func New(profile Profile) (Provider, error) {
+	switch profile.Provider {
+	case "openai":
+		return newOpenAI(profile)
+	case "anthropic":
+		return newAnthropic(profile)
+	case "gemini":
+		return newGemini(profile)
+	case "ollama":
+		return newOllama(profile), nil
+	case "openai-compatible":
+		return newCompatible(profile)
+	default:
+		return nil, ErrUnknownProvider
+	}
+}
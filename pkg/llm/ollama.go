@@ -0,0 +1,76 @@
+package llm
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+const defaultOllamaBaseURL = "http://localhost:11434/api/chat"
+
+// ollama talks to a local Ollama instance, no auth required.
+type ollama struct {
+	baseURL     string
+	model       string
+	temperature float64
+}
+
+func newOllama(profile Profile) *ollama {
+	baseURL := profile.BaseURL
+	if baseURL == "" {
+		baseURL = defaultOllamaBaseURL
+	}
+
+	return &ollama{
+		baseURL:     baseURL,
+		model:       profile.Model,
+		temperature: profile.Temperature,
+	}
+}
+
+func (m *ollama) Magic(message string) (string, error) {
+	reqBodyRaw, _ := json.Marshal(map[string]interface{}{
+		"model":  m.model,
+		"stream": false,
+		"messages": []map[string]string{
+			{
+				"content": message,
+				"role":    "user",
+			},
+		},
+		"options": map[string]interface{}{
+			"temperature": m.temperature,
+		},
+	})
+
+	reqBody := bytes.NewBuffer(reqBodyRaw)
+	req, _ := http.NewRequest(http.MethodPost, m.baseURL, reqBody)
+	req.Header.Add("Content-Type", "application/json")
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+
+	defer res.Body.Close()
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return "", err
+	}
+
+	resContent := struct {
+		Message struct {
+			Content string `json:"content"`
+		} `json:"message"`
+	}{}
+	if err := json.Unmarshal(resBody, &resContent); err != nil {
+		return "", err
+	}
+
+	if resContent.Message.Content == "" {
+		return "", ErrLLMAPI
+	}
+
+	return resContent.Message.Content, nil
+}
@@ -0,0 +1,210 @@
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const defaultOpenAIBaseURL = "https://api.openai.com/v1/chat/completions"
+
+// openAI talks to OpenAI's chat completions API.
+type openAI struct {
+	apiKey      string
+	baseURL     string
+	model       string
+	temperature float64
+}
+
+func newOpenAI(profile Profile) (*openAI, error) {
+	key, err := apiKey(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	baseURL := profile.BaseURL
+	if baseURL == "" {
+		baseURL = defaultOpenAIBaseURL
+	}
+
+	return &openAI{
+		apiKey:      key,
+		baseURL:     baseURL,
+		model:       profile.Model,
+		temperature: profile.Temperature,
+	}, nil
+}
+
+func (m *openAI) Magic(message string) (string, error) {
+	reqBodyRaw, _ := json.Marshal(map[string]interface{}{
+		"model":       m.model,
+		"temperature": m.temperature,
+		"messages": []map[string]string{
+			{
+				"content": message,
+				"role":    "user",
+			},
+		},
+	})
+
+	reqBody := bytes.NewBuffer(reqBodyRaw)
+	req, _ := http.NewRequest(http.MethodPost, m.baseURL, reqBody)
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", m.apiKey))
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+
+	defer res.Body.Close()
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return "", err
+	}
+
+	resContent := struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+	}{}
+	if err := json.Unmarshal(resBody, &resContent); err != nil {
+		return "", err
+	}
+
+	if len(resContent.Choices) == 0 {
+		return "", ErrLLMAPI
+	}
+
+	return resContent.Choices[0].Message.Content, nil
+}
+
+// MagicStream asks OpenAI for a server-sent-events stream (stream: true) and
+// forwards each token delta on the returned channel as it arrives. The
+// channel is closed once the stream ends or errors out.
+func (m *openAI) MagicStream(message string) (<-chan string, error) {
+	reqBodyRaw, _ := json.Marshal(map[string]interface{}{
+		"model":       m.model,
+		"temperature": m.temperature,
+		"stream":      true,
+		"messages": []map[string]string{
+			{
+				"content": message,
+				"role":    "user",
+			},
+		},
+	})
+
+	reqBody := bytes.NewBuffer(reqBodyRaw)
+	req, _ := http.NewRequest(http.MethodPost, m.baseURL, reqBody)
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", m.apiKey))
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := make(chan string)
+	go func() {
+		defer res.Body.Close()
+		defer close(tokens)
+
+		scanner := bufio.NewScanner(res.Body)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if !strings.HasPrefix(line, "data: ") {
+				continue
+			}
+
+			data := strings.TrimPrefix(line, "data: ")
+			if data == "[DONE]" {
+				return
+			}
+
+			chunk := struct {
+				Choices []struct {
+					Delta struct {
+						Content string `json:"content"`
+					} `json:"delta"`
+				} `json:"choices"`
+			}{}
+			if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+				continue
+			}
+
+			if len(chunk.Choices) > 0 && chunk.Choices[0].Delta.Content != "" {
+				tokens <- chunk.Choices[0].Delta.Content
+			}
+		}
+	}()
+
+	return tokens, nil
+}
+
+// MagicJSON asks OpenAI to constrain its response to schemaJSON using
+// response_format: {"type": "json_schema"}.
+func (m *openAI) MagicJSON(message, schemaJSON string) (string, error) {
+	var rawSchema interface{}
+	if err := json.Unmarshal([]byte(schemaJSON), &rawSchema); err != nil {
+		return "", err
+	}
+
+	reqBodyRaw, _ := json.Marshal(map[string]interface{}{
+		"model":       m.model,
+		"temperature": m.temperature,
+		"messages": []map[string]string{
+			{
+				"content": message,
+				"role":    "user",
+			},
+		},
+		"response_format": map[string]interface{}{
+			"type": "json_schema",
+			"json_schema": map[string]interface{}{
+				"name":   "steps",
+				"schema": rawSchema,
+				"strict": true,
+			},
+		},
+	})
+
+	reqBody := bytes.NewBuffer(reqBodyRaw)
+	req, _ := http.NewRequest(http.MethodPost, m.baseURL, reqBody)
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", m.apiKey))
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+
+	defer res.Body.Close()
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return "", err
+	}
+
+	resContent := struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+	}{}
+	if err := json.Unmarshal(resBody, &resContent); err != nil {
+		return "", err
+	}
+
+	if len(resContent.Choices) == 0 {
+		return "", ErrLLMAPI
+	}
+
+	return resContent.Choices[0].Message.Content, nil
+}
@@ -0,0 +1,7 @@
+package llm
+
+// StreamProvider is implemented by providers that can stream tokens as they
+// are generated instead of waiting for the full response, see pkg/tui.
+type StreamProvider interface {
+	MagicStream(message string) (<-chan string, error)
+}
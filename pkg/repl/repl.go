@@ -0,0 +1,147 @@
+// Package repl implements howto's interactive mode: a prompt that keeps
+// asking questions until the user quits, with in-memory up/down history
+// and a few `:`-prefixed meta commands.
+package repl
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	prompt "github.com/c-bata/go-prompt"
+
+	"github.com/ed-henrique/how-to-job/pkg/history"
+)
+
+// maxSeedHistory bounds how many past prompts are preloaded into the
+// REPL's up/down history on start.
+const maxSeedHistory = 50
+
+// Session wires the REPL to the rest of howto without the REPL needing to
+// know about providers, config, or rendering.
+type Session struct {
+	// Ask answers a question and prints the rendered result itself. An
+	// error (a transient API failure, a response that never validated,
+	// ...) is reported to the user without ending the session.
+	Ask func(message string) error
+	// SwitchModel changes the active model profile mid-session.
+	SwitchModel func(name string) error
+}
+
+var metaCommands = []prompt.Suggest{
+	{Text: ":e", Description: "Write a new prompt in $EDITOR, starting from an empty file"},
+	{Text: ":model", Description: "Switch the active model profile"},
+	{Text: ":clear", Description: "Clear the screen"},
+	{Text: ":quit", Description: "Exit the REPL"},
+}
+
+func completer(d prompt.Document) []prompt.Suggest {
+	return prompt.FilterHasPrefix(metaCommands, d.GetWordBeforeCursor(), true)
+}
+
+// Run starts the interactive REPL. It blocks until the user quits with
+// :quit, :q, or Ctrl-D.
+func Run(session Session) {
+	executor := func(line string) {
+		handleLine(session, strings.TrimSpace(line))
+	}
+
+	prompt.New(
+		executor,
+		completer,
+		prompt.OptionPrefix("howto> "),
+		prompt.OptionPrefixTextColor(prompt.Blue),
+		prompt.OptionHistory(seedHistory()),
+	).Run()
+}
+
+// seedHistory preloads the REPL's in-memory up/down history with past
+// prompts, oldest first, so recall works from the very first keystroke
+// instead of only within the current session.
+func seedHistory() []string {
+	store, err := history.Open()
+	if err != nil {
+		return nil
+	}
+	defer store.Close()
+
+	entries, err := store.List("")
+	if err != nil {
+		return nil
+	}
+
+	if len(entries) > maxSeedHistory {
+		entries = entries[:maxSeedHistory]
+	}
+
+	seeded := make([]string, len(entries))
+	for i, e := range entries {
+		// entries is most-recent-first; reverse so the most recent prompt
+		// is the first one the up arrow recalls.
+		seeded[len(entries)-1-i] = e.Prompt
+	}
+
+	return seeded
+}
+
+func handleLine(session Session, line string) {
+	switch {
+	case line == "":
+		return
+	case line == ":quit" || line == ":q":
+		os.Exit(0)
+	case line == ":clear":
+		fmt.Print("\033[H\033[2J")
+	case line == ":e":
+		if edited, err := openEditor(); err == nil && strings.TrimSpace(edited) != "" {
+			if err := session.Ask(strings.TrimSpace(edited)); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+			}
+		}
+	case strings.HasPrefix(line, ":model"):
+		name := strings.TrimSpace(strings.TrimPrefix(line, ":model"))
+		if name == "" {
+			fmt.Println("usage: :model <name>")
+			return
+		}
+		if err := session.SwitchModel(name); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		}
+	default:
+		if err := session.Ask(line); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		}
+	}
+}
+
+// openEditor opens $EDITOR (falling back to vi) on a scratch file and
+// returns what the user wrote, for composing multi-line questions.
+func openEditor() (string, error) {
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = "vi"
+	}
+
+	f, err := os.CreateTemp("", "howto-*.md")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+	f.Close()
+
+	cmd := exec.Command(editor, f.Name())
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", err
+	}
+
+	raw, err := os.ReadFile(f.Name())
+	if err != nil {
+		return "", err
+	}
+
+	return string(raw), nil
+}
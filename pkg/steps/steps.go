@@ -0,0 +1,124 @@
+// Package steps asks an llm.Provider for step-by-step instructions in a
+// structured, schema-validated shape instead of parsing free-form markdown.
+package steps
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ed-henrique/how-to-job/pkg/llm"
+	"github.com/invopop/jsonschema"
+)
+
+const (
+	minSteps = 3
+	maxSteps = 10
+)
+
+// Steps is the structured shape the model is asked to respond in.
+type Steps struct {
+	Title string   `json:"title" jsonschema:"description=A short title for the task that starts with a verb,required"`
+	Steps []string `json:"steps" jsonschema:"minItems=3,maxItems=10,description=The ordered list of steps to accomplish the task,required"`
+}
+
+var errInvalidSteps = errors.New("response did not match the steps schema")
+
+func schema() ([]byte, error) {
+	reflector := jsonschema.Reflector{DoNotReference: true}
+	return json.Marshal(reflector.Reflect(&Steps{}))
+}
+
+// Prompt builds the same schema-validated prompt Generate uses, for
+// callers that drive a provider directly instead of going through
+// Generate, such as a streaming path that can't retry mid-stream.
+func Prompt(message string) (string, error) {
+	schemaRaw, err := schema()
+	if err != nil {
+		return "", err
+	}
+
+	return promptFor(message, string(schemaRaw)), nil
+}
+
+// Generate asks provider for Steps describing message, validating the
+// response against the Steps schema and retrying up to maxRetries times,
+// feeding the validation error back to the model so it can self-correct.
+func Generate(provider llm.Provider, message string, maxRetries int) (Steps, error) {
+	schemaRaw, err := schema()
+	if err != nil {
+		return Steps{}, err
+	}
+
+	conversation := promptFor(message, string(schemaRaw))
+
+	var lastErr error
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		raw, err := ask(provider, conversation, string(schemaRaw))
+		if err != nil {
+			return Steps{}, err
+		}
+
+		result, verr := Parse(raw)
+		if verr == nil {
+			return result, nil
+		}
+
+		lastErr = verr
+		conversation = fmt.Sprintf("%s\n\nYour previous response was:\n\n%s\n\nThat response was invalid: %s. Respond again with ONLY the corrected JSON object, matching the schema exactly.", conversation, raw, verr)
+	}
+
+	return Steps{}, fmt.Errorf("gave up after %d retries: %w", maxRetries, lastErr)
+}
+
+func ask(provider llm.Provider, message, schemaJSON string) (string, error) {
+	if jp, ok := provider.(llm.JSONProvider); ok {
+		return jp.MagicJSON(message, schemaJSON)
+	}
+
+	return provider.Magic(message)
+}
+
+// Parse extracts and validates a Steps value from the model's raw response.
+// It's exported so callers that can't use Generate's retry loop (e.g. once
+// a stream has already closed) can still validate what they got.
+func Parse(raw string) (Steps, error) {
+	raw = stripCodeFence(strings.TrimSpace(raw))
+
+	var s Steps
+	if err := json.Unmarshal([]byte(raw), &s); err != nil {
+		return Steps{}, fmt.Errorf("%w: %s", errInvalidSteps, err)
+	}
+
+	if s.Title == "" {
+		return Steps{}, fmt.Errorf("%w: title is empty", errInvalidSteps)
+	}
+
+	if len(s.Steps) < minSteps || len(s.Steps) > maxSteps {
+		return Steps{}, fmt.Errorf("%w: got %d steps, want between %d and %d", errInvalidSteps, len(s.Steps), minSteps, maxSteps)
+	}
+
+	return s, nil
+}
+
+// stripCodeFence removes a leading/trailing ```json fence, in case the model
+// ignores the "no markdown fences" instruction.
+func stripCodeFence(raw string) string {
+	raw = strings.TrimPrefix(raw, "```json")
+	raw = strings.TrimPrefix(raw, "```")
+	raw = strings.TrimSuffix(raw, "```")
+	return strings.TrimSpace(raw)
+}
+
+// Render turns a validated Steps value into the markdown glamour expects.
+func Render(s Steps) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# How To %s\n\n## Steps\n\n", s.Title)
+	for i, step := range s.Steps {
+		fmt.Fprintf(&b, "%d. %s\n", i+1, step)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,89 @@
+package steps
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    Steps
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			raw:  `{"title":"Make Coffee","steps":["Boil water","Add grounds","Pour"]}`,
+			want: Steps{Title: "Make Coffee", Steps: []string{"Boil water", "Add grounds", "Pour"}},
+		},
+		{
+			name: "wrapped in a code fence",
+			raw:  "```json\n" + `{"title":"Make Coffee","steps":["Boil water","Add grounds","Pour"]}` + "\n```",
+			want: Steps{Title: "Make Coffee", Steps: []string{"Boil water", "Add grounds", "Pour"}},
+		},
+		{
+			name:    "invalid JSON",
+			raw:     `{"title": "Make Coffee"`,
+			wantErr: true,
+		},
+		{
+			name:    "empty title",
+			raw:     `{"title":"","steps":["a","b","c"]}`,
+			wantErr: true,
+		},
+		{
+			name:    "too few steps",
+			raw:     `{"title":"Make Coffee","steps":["a","b"]}`,
+			wantErr: true,
+		},
+		{
+			name:    "too many steps",
+			raw:     `{"title":"Make Coffee","steps":["1","2","3","4","5","6","7","8","9","10","11"]}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.raw)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Parse(%q) = nil error, want one", tt.raw)
+				}
+				if !errors.Is(err, errInvalidSteps) {
+					t.Errorf("Parse(%q) error = %v, want wrapping errInvalidSteps", tt.raw, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Parse(%q) unexpected error: %v", tt.raw, err)
+			}
+
+			if got.Title != tt.want.Title || len(got.Steps) != len(tt.want.Steps) {
+				t.Errorf("Parse(%q) = %+v, want %+v", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStripCodeFence(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{name: "no fence", raw: `{"a":1}`, want: `{"a":1}`},
+		{name: "json fence", raw: "```json\n{\"a\":1}\n```", want: `{"a":1}`},
+		{name: "bare fence", raw: "```\n{\"a\":1}\n```", want: `{"a":1}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stripCodeFence(tt.raw); got != tt.want {
+				t.Errorf("stripCodeFence(%q) = %q, want %q", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
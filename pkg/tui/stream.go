@@ -0,0 +1,100 @@
+// Package tui renders a streaming LLM response live: a spinner while
+// waiting for the first token, then a progressively re-rendered glamour
+// view as tokens arrive.
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/glamour"
+)
+
+type tokenMsg string
+type doneMsg struct{}
+
+type model struct {
+	spinner  spinner.Model
+	style    string
+	raw      *strings.Builder
+	tokens   <-chan string
+	received bool
+}
+
+func waitForToken(tokens <-chan string) tea.Cmd {
+	return func() tea.Msg {
+		token, ok := <-tokens
+		if !ok {
+			return doneMsg{}
+		}
+
+		return tokenMsg(token)
+	}
+}
+
+func initialModel(tokens <-chan string, style string) model {
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+
+	return model{spinner: s, style: style, raw: &strings.Builder{}, tokens: tokens}
+}
+
+func (m model) Init() tea.Cmd {
+	return tea.Batch(m.spinner.Tick, waitForToken(m.tokens))
+}
+
+func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tokenMsg:
+		m.received = true
+		m.raw.WriteString(string(msg))
+		return m, waitForToken(m.tokens)
+	case doneMsg:
+		return m, tea.Quit
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		return m, nil
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+	default:
+		return m, nil
+	}
+}
+
+func (m model) View() string {
+	if !m.received {
+		return fmt.Sprintf("\n %s Thinking...\n\n", m.spinner.View())
+	}
+
+	out, err := glamour.Render(m.raw.String(), m.style)
+	if err != nil {
+		return m.raw.String()
+	}
+
+	return out
+}
+
+// Stream runs a Bubble Tea program that renders tokens as they arrive on
+// tokens, progressively re-rendering through glamour using style, and
+// returns the raw text it received, trimmed, once the stream closes.
+func Stream(tokens <-chan string, style string) (string, error) {
+	p := tea.NewProgram(initialModel(tokens, style))
+
+	finalModel, err := p.Run()
+	if err != nil {
+		return "", err
+	}
+
+	m, ok := finalModel.(model)
+	if !ok {
+		return "", fmt.Errorf("unexpected final model type %T", finalModel)
+	}
+
+	return strings.TrimSpace(m.raw.String()), nil
+}